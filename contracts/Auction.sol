@@ -84,6 +84,12 @@ contract Auction {
         BLS12381.G1Point memory _hPoint
     ) payable {
         require(msg.value > 0, "Purchaser must deposit");
+        // With a single bidder T_0 is the identity, so every bit would read as 1
+        require(_whitelist.length >= 2, "Need at least two bidders");
+        // An identity or shared generator makes every commitment trivially openable
+        require(!BLS12381.isInfinity(_gPoint), "G is the identity");
+        require(!BLS12381.isInfinity(_hPoint), "H is the identity");
+        require(!BLS12381.eq(_gPoint, _hPoint), "G and H must differ");
 
         purchaser = msg.sender;
         deposit   = msg.value;
@@ -93,6 +99,8 @@ contract Auction {
         H_POINT   = _hPoint;
 
         for (uint256 i = 0; i < _whitelist.length; i++) {
+            // N counts entries, so a repeated address leaves a slot no one can fill
+            require(!whitelisted[_whitelist[i]], "Duplicate bidder");
             whitelisted[_whitelist[i]] = true;
         }
         // bitCommitSums default to (0,0,0,0) = point at infinity — correct identity
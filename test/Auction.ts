@@ -2,7 +2,7 @@ import { loadFixture } from "@nomicfoundation/hardhat-toolbox-viem/network-helpe
 import { expect } from "chai";
 import hre from "hardhat";
 import { getAddress, parseEther } from "viem";
-import { Bidder, G_POINT, H_POINT, L, N, pointToViem } from "../utils";
+import { Bidder, G_POINT, G_ZERO, H_POINT, L, N, pointToViem, validateGenerators, viemToPoint, winners } from "../utils";
 
 const G_VIEM = pointToViem(G_POINT);
 const H_VIEM = pointToViem(H_POINT);
@@ -21,6 +21,9 @@ describe("Auction", function () {
       value: DEPOSIT,
     });
 
+    // Clients must not trust the deployer's generators: check the stored ones
+    validateGenerators(viemToPoint(await auction.read.G_POINT()), viemToPoint(await auction.read.H_POINT()));
+
     const publicClient = await hre.viem.getPublicClient();
     return { auction, purchaser, bidderWallets, publicClient };
   }
@@ -47,6 +50,53 @@ describe("Auction", function () {
       expect(await auction.read.purchaser()).to.equal(getAddress(purchaser.account.address));
       expect(await auction.read.N()).to.equal(BigInt(bidderWallets.length));
     });
+
+    it("rejects an empty whitelist", async function () {
+      await expect(
+        hre.viem.deployContract("Auction", [[], G_VIEM, H_VIEM], { value: DEPOSIT })
+      ).to.be.rejectedWith("Need at least two bidders");
+    });
+
+    it("rejects a single-bidder whitelist", async function () {
+      const [, bidder1] = await hre.viem.getWalletClients();
+      await expect(
+        hre.viem.deployContract("Auction", [[getAddress(bidder1.account.address)], G_VIEM, H_VIEM], {
+          value: DEPOSIT,
+        })
+      ).to.be.rejectedWith("Need at least two bidders");
+    });
+
+    it("rejects a whitelist with a repeated address", async function () {
+      const [, bidder1] = await hre.viem.getWalletClients();
+      const addr = getAddress(bidder1.account.address);
+      await expect(
+        hre.viem.deployContract("Auction", [[addr, addr], G_VIEM, H_VIEM], { value: DEPOSIT })
+      ).to.be.rejectedWith("Duplicate bidder");
+    });
+
+    it("rejects G at the identity", async function () {
+      const [, bidder1, bidder2] = await hre.viem.getWalletClients();
+      const whitelist = [bidder1, bidder2].map((b) => getAddress(b.account.address));
+      await expect(
+        hre.viem.deployContract("Auction", [whitelist, pointToViem(G_ZERO), H_VIEM], { value: DEPOSIT })
+      ).to.be.rejectedWith("G is the identity");
+    });
+
+    it("rejects H at the identity", async function () {
+      const [, bidder1, bidder2] = await hre.viem.getWalletClients();
+      const whitelist = [bidder1, bidder2].map((b) => getAddress(b.account.address));
+      await expect(
+        hre.viem.deployContract("Auction", [whitelist, G_VIEM, pointToViem(G_ZERO)], { value: DEPOSIT })
+      ).to.be.rejectedWith("H is the identity");
+    });
+
+    it("rejects H equal to G", async function () {
+      const [, bidder1, bidder2] = await hre.viem.getWalletClients();
+      const whitelist = [bidder1, bidder2].map((b) => getAddress(b.account.address));
+      await expect(
+        hre.viem.deployContract("Auction", [whitelist, G_VIEM, G_VIEM], { value: DEPOSIT })
+      ).to.be.rejectedWith("G and H must differ");
+    });
   });
 
   // ─── Add Bidders ───────────────────────────────────────────────────────────
@@ -80,8 +130,9 @@ describe("Auction", function () {
 
       // Compute tally keys from on-chain public keys
       const allPubXs = await auction.read.getPublicXs();
+      const n        = Number(await auction.read.N());
       for (const bidder of bidders) {
-        bidder.computeBitCommitments(allPubXs);
+        bidder.computeBitCommitments(allPubXs, n);
       }
 
       // Phase 3: submit bit commitments MSB → LSB
@@ -101,7 +152,7 @@ describe("Auction", function () {
         if (clearingPriceBit === 0) {
           // Bidders with bit=1 at this position have lost
           for (const bidder of bidders) {
-            if (bidder.bidBinary[j] === 1) bidder.isLost = true;
+            if (bidder.bidBinary[j] === 1) bidder.eliminate(j);
           }
         }
       }
@@ -113,7 +164,7 @@ describe("Auction", function () {
 
       // Phase 4: declare winner
       const minBid      = Math.min(...bids);
-      const winnerIndex = bids.indexOf(minBid);
+      const winnerIndex = winners(bids, Number(clearingPrice))[0];
       const winnerBidder = bidders[winnerIndex];
 
       await auction.write.declareWinner([winnerBidder.salt], {
@@ -0,0 +1,222 @@
+import { expect } from "chai";
+import { Bidder, isWinner, winners, clearingPriceBounds, G_ZERO, pointAdd, pointNeg, pointToViem, viemToPoint } from "../utils";
+
+/**
+ * Run the MSB→LSB reveal off-chain, mirroring Auction.submitBitCommitment:
+ * a position's bit is 1 iff the sum of all submitted cryptograms is the identity.
+ */
+function revealClearingPrice(bids: number[], bitLength: number): number {
+  return revealClearingBits(bids, bitLength, bitLength).reduce((price, bit) => price * 2 + bit, 0);
+}
+
+/** Run the off-chain reveal for the first `positions` bits (MSB first) and return those bits. */
+function revealClearingBits(bids: number[], bitLength: number, positions: number): number[] {
+  const bidders  = bids.map((bid, i) => new Bidder(i, bid, bitLength));
+  const allPubXs = bidders.map((b) => b.pubX);
+  for (const b of bidders) b.computeBitCommitments(allPubXs, bids.length);
+
+  const bits: number[] = [];
+  for (let j = 0; j < positions; j++) {
+    let sum = G_ZERO;
+    for (const b of bidders) {
+      const bitCommit =
+        b.bidBinary[j] === 0 && !b.isLost ? b.bitZeroCommitments[j] : b.bitOneCommitments[j];
+      sum = pointAdd(sum, viemToPoint(bitCommit));
+    }
+
+    const bit = sum.equals(G_ZERO) ? 1 : 0;
+    bits.push(bit);
+    if (bit === 0) {
+      for (const b of bidders) if (b.bidBinary[j] === 1) b.eliminate(j);
+    }
+  }
+  return bits;
+}
+
+describe("Bidder", function () {
+  const bids    = [583, 324, 903, 785];
+  const bidders = bids.map((bid, i) => new Bidder(i, bid));
+
+  describe("constructor", function () {
+    it("should reject a bid that does not fit in the bit length", function () {
+      expect(() => new Bidder(0, 8, 3)).to.throw("does not fit in 3 bits");
+    });
+  });
+
+  describe("computeBitCommitments", function () {
+    it("should reject a bidder with the wrong number of public keys", function () {
+      const allPubXs = bidders.map((b) => b.pubX);
+      allPubXs[2] = allPubXs[2].slice(0, -1);
+      expect(() => bidders[0].computeBitCommitments(allPubXs, 4)).to.throw("bidder 2");
+    });
+
+    it("should reject a key list from before every bidder has joined", function () {
+      const joined = bidders.slice(0, 3).map((b) => b.pubX);
+      expect(() => bidders[0].computeBitCommitments(joined, 4)).to.throw(
+        "Expected public keys from 4 bidders, got 3"
+      );
+    });
+
+    it("should reject two bidders sharing a public key", function () {
+      const allPubXs = bidders.map((b) => [...b.pubX]);
+      allPubXs[3][5] = allPubXs[1][5];
+      expect(() => bidders[0].computeBitCommitments(allPubXs, 4)).to.throw(
+        "bidders 1 and 3 share X at bit 5"
+      );
+    });
+
+    it("should reject an identity tally key for a lone bidder", function () {
+      const lone = new Bidder(0, 5, 3);
+      expect(() => lone.computeBitCommitments([lone.pubX], 1)).to.throw("T_0 is the identity at bit 0");
+    });
+
+    it("should reject crafted keys that cancel out the tally key", function () {
+      const allPubXs = bidders.slice(0, 3).map((b) => [...b.pubX]);
+      allPubXs[2][4] = pointToViem(pointNeg(viemToPoint(allPubXs[1][4])));
+      expect(() => bidders[0].computeBitCommitments(allPubXs, 3)).to.throw("T_0 is the identity at bit 4");
+    });
+
+    it("should reject a key list that is not in bidder id order", function () {
+      const allPubXs = bidders.map((b) => b.pubX);
+      [allPubXs[0], allPubXs[1]] = [allPubXs[1], allPubXs[0]];
+      expect(() => bidders[1].computeBitCommitments(allPubXs, 4)).to.throw("not at index 1");
+      expect(() => new Bidder(7, 5).computeBitCommitments(bidders.map((b) => b.pubX), 4)).to.throw("not at index 7");
+    });
+
+    it("should produce one commitment pair per bit", function () {
+      const allPubXs = bidders.map((b) => b.pubX);
+      bidders[0].computeBitCommitments(allPubXs, 4);
+      expect(bidders[0].bitZeroCommitments).to.have.length(allPubXs[0].length);
+      expect(bidders[0].bitOneCommitments).to.have.length(allPubXs[0].length);
+    });
+
+    it("should support a bit length and bidder count other than the defaults", function () {
+      const small    = [5, 3, 6].map((bid, i) => new Bidder(i, bid, 3));
+      const allPubXs = small.map((b) => b.pubX);
+      small[1].computeBitCommitments(allPubXs, 3);
+      expect(small[1].bidBinary).to.deep.equal([0, 1, 1]);
+      expect(small[1].bitZeroCommitments).to.have.length(3);
+    });
+
+    it("should cancel to the identity when every bidder sends the bit-1 encoding", function () {
+      const small    = [5, 3, 6].map((bid, i) => new Bidder(i, bid, 3));
+      const allPubXs = small.map((b) => b.pubX);
+      for (const b of small) b.computeBitCommitments(allPubXs, 3);
+
+      let sum = G_ZERO;
+      for (const b of small) sum = pointAdd(sum, viemToPoint(b.bitOneCommitments[0]));
+      expect(sum.equals(G_ZERO)).to.be.true;
+    });
+  });
+
+  describe("lost bidders", function () {
+    const cases = [
+      [1, 2, 3],
+      [6, 3, 5],
+      [4, 4, 7],
+      [7, 1, 7],
+      [2, 6, 3, 5],
+      [5, 4, 6, 4],
+    ];
+
+    for (const bids of cases) {
+      it(`should reveal the minimum of [${bids}] without lost bidders flipping a bit`, function () {
+        expect(revealClearingPrice(bids, 3)).to.equal(Math.min(...bids));
+      });
+    }
+  });
+
+  describe("clearingPriceBounds", function () {
+    const cases = [
+      [37, 12, 50, 63],
+      [0, 63, 31, 32],
+      [20, 20, 41, 58],
+      [63, 62, 61, 60],
+    ];
+
+    for (const bids of cases) {
+      it(`should bound the minimum of [${bids}] after each revealed position`, function () {
+        const price = Math.min(...bids);
+        const bits  = revealClearingBits(bids, 6, 6);
+        for (let m = 0; m <= 6; m++) {
+          const { lower, upper } = clearingPriceBounds(bits.slice(0, m), 6);
+          expect(price).to.be.within(lower, upper);
+          expect(upper - lower).to.equal(2 ** (6 - m) - 1);
+        }
+      });
+    }
+  });
+
+  describe("status", function () {
+    it("should record the bit at which a bidder was eliminated", function () {
+      const b = new Bidder(0, 5, 3);
+      expect(b.status).to.equal("active");
+      expect(b.isLost).to.be.false;
+
+      b.eliminate(1);
+      b.eliminate(2);
+      expect(b.status).to.equal("eliminated");
+      expect(b.reason).to.equal("Eliminated at bit 1");
+      expect(b.isLost).to.be.true;
+    });
+
+    it("should let disqualification override elimination", function () {
+      const b = new Bidder(0, 5, 3);
+      b.eliminate(0);
+      b.disqualify("Invalid range proof");
+      b.eliminate(1);
+      expect(b.status).to.equal("disqualified");
+      expect(b.reason).to.equal("Invalid range proof");
+      expect(b.isLost).to.be.true;
+    });
+
+    it("should not let a disqualified bidder be reinstated by assignment", function () {
+      const b = new Bidder(0, 5, 3);
+      b.disqualify("Invalid range proof");
+      for (const prop of ["isLost", "status", "reason"]) {
+        expect(Object.getOwnPropertyDescriptor(Bidder.prototype, prop)?.set).to.be.undefined;
+      }
+      try { (b as any).isLost = false; } catch { /* getter-only in strict mode */ }
+      try { (b as any).status = "active"; } catch { /* getter-only in strict mode */ }
+      expect(b.status).to.equal("disqualified");
+      expect(b.isLost).to.be.true;
+
+      b.reset(5);
+      expect(b.status).to.equal("active");
+    });
+  });
+
+  describe("reset", function () {
+    it("should draw fresh keys for the new bid and clear state", function () {
+      const b = new Bidder(1, 5, 3);
+      const oldPubX       = b.pubX;
+      const oldCommitment = b.commitment;
+      b.computeBitCommitments([new Bidder(0, 2, 3).pubX, b.pubX], 2);
+      b.eliminate(0);
+
+      b.reset(6);
+      expect(b.id).to.equal(1);
+      expect(b.bid).to.equal(6);
+      expect(b.bidBinary).to.deep.equal([1, 1, 0]);
+      expect(b.pubX).to.have.length(3);
+      expect(b.pubX[0]).to.not.deep.equal(oldPubX[0]);
+      expect(b.commitment).to.not.deep.equal(oldCommitment);
+      expect(b.bitZeroCommitments).to.be.empty;
+      expect(b.status).to.equal("active");
+      expect(b.isLost).to.be.false;
+    });
+  });
+
+  describe("winners", function () {
+    it("should only count a bid exactly at the clearing price", function () {
+      expect(isWinner(324, 324)).to.be.true;
+      expect(isWinner(325, 324)).to.be.false;
+      expect(isWinner(323, 324)).to.be.false;
+    });
+
+    it("should return every tied winner", function () {
+      expect(winners([583, 324, 903, 785], 324)).to.deep.equal([1]);
+      expect(winners([583, 324, 903, 324], 324)).to.deep.equal([1, 3]);
+    });
+  });
+});
@@ -1,5 +1,25 @@
 import { expect } from "chai";
-import { intToBits, bitsToInt, intToBitsMSB, pedersenCommit, pointToViem, viemToPoint, G_POINT, H_POINT, randomScalar } from "../utils";
+import { randomBytes } from "crypto";
+import { intToBits, bitsToInt, bigIntToBits, bitsToBigInt, intToBitsMSB, intToBitsMSBChecked, intToBitsOrder, bitsToIntOrder, clearingPriceBounds, pedersenCommit, pointToViem, viemToPoint, G_POINT, H_POINT, randomScalar, wideBytesToScalar, deriveH, validateGenerators, G_ZERO, Fr, Fp, CURVE, G1Point } from "../utils";
+
+/**
+ * An on-curve G1 point outside the prime-order subgroup: lift the first x with
+ * y^2 = x^3 + 4 without clearing the cofactor.
+ */
+function nonSubgroupPoint(): G1Point {
+  for (let x = 1n; ; x++) {
+    const rhs = Fp.add(Fp.mul(Fp.sqr(x), x), 4n);
+    let y: bigint;
+    try {
+      y = Fp.sqrt(rhs);
+    } catch {
+      continue; // rhs is not a square
+    }
+    if (!Fp.eql(Fp.sqr(y), rhs)) continue;
+    const p = CURVE.G1.Point.fromAffine({ x, y });
+    if (!p.isTorsionFree()) return p;
+  }
+}
 
 describe("Math Utils", function () {
   describe("intToBits and bitsToInt (LSB-first)", function () {
@@ -11,6 +31,23 @@ describe("Math Utils", function () {
     });
   });
 
+  describe("bigIntToBits and bitsToBigInt (LSB-first)", function () {
+    it("should round-trip values above 2^63", function () {
+      const n    = (1n << 70n) + 12345n;
+      const bits = bigIntToBits(n, 72);
+      expect(bits).to.have.length(72);
+      expect(bits[70]).to.equal(1);
+      expect(bitsToBigInt(bits)).to.equal(n);
+    });
+
+    it("should agree with intToBits/bitsToInt for small values", function () {
+      for (const n of [0, 1, 13, 583, 65535]) {
+        expect(bigIntToBits(BigInt(n), 16)).to.deep.equal(intToBits(n, 16));
+        expect(bitsToBigInt(intToBits(n, 16))).to.equal(BigInt(bitsToInt(intToBits(n, 16))));
+      }
+    });
+  });
+
   describe("intToBitsMSB", function () {
     it("should produce MSB-first bit array", function () {
       expect(intToBitsMSB(13, 4)).to.deep.equal([1, 1, 0, 1]);
@@ -18,6 +55,81 @@ describe("Math Utils", function () {
     });
   });
 
+  describe("intToBitsOrder and bitsToIntOrder", function () {
+    it("should match intToBits for LSB-first and intToBitsMSB for MSB-first", function () {
+      expect(intToBitsOrder(13, 4, "lsb")).to.deep.equal(intToBits(13, 4));
+      expect(intToBitsOrder(13, 4, "msb")).to.deep.equal(intToBitsMSB(13, 4));
+    });
+
+    it("should round-trip in either order", function () {
+      for (const n of [0, 1, 583, 65535]) {
+        expect(bitsToIntOrder(intToBitsOrder(n, 16, "lsb"), "lsb")).to.equal(n);
+        expect(bitsToIntOrder(intToBitsOrder(n, 16, "msb"), "msb")).to.equal(n);
+      }
+    });
+
+    it("should weight MSB-first bits like the contract's clearing price", function () {
+      // Auction._bitsToPrice: clearingPriceBits[0] is the MSB
+      expect(bitsToIntOrder([0, 1, 0, 0, 0, 1, 0, 1, 0, 0, 0, 0, 0, 0, 1, 1], "msb")).to.equal(0x4503);
+    });
+
+    it("should not wrap at the 32-bit boundary", function () {
+      for (const n of [2 ** 31, 2 ** 32 - 1, 2 ** 32 + 5]) {
+        expect(intToBitsOrder(n, 40, "msb")).to.deep.equal(intToBitsMSB(n, 40));
+        expect(bitsToIntOrder(intToBitsOrder(n, 40, "msb"), "msb")).to.equal(n);
+        expect(bitsToIntOrder(intToBitsOrder(n, 40, "lsb"), "lsb")).to.equal(n);
+      }
+    });
+
+    it("should reject values beyond Number.MAX_SAFE_INTEGER", function () {
+      expect(() => bitsToIntOrder(new Array(54).fill(1), "lsb")).to.throw("exceeds Number.MAX_SAFE_INTEGER");
+      expect(() => intToBitsOrder(-1, 8, "lsb")).to.throw("not a non-negative integer");
+    });
+
+    it("should reject values that overflow the width", function () {
+      expect(() => intToBitsOrder(20, 4, "msb")).to.throw("does not fit in 4 bits");
+      expect(() => intToBitsOrder(16, 4, "lsb")).to.throw("does not fit in 4 bits");
+      expect(() => intToBitsOrder(2 ** 32, 32, "msb")).to.throw("does not fit in 32 bits");
+      expect(intToBitsOrder(15, 4, "msb")).to.deep.equal([1, 1, 1, 1]);
+    });
+  });
+
+  describe("clearingPriceBounds", function () {
+    it("should span the whole range with no bits decided", function () {
+      expect(clearingPriceBounds([], 4)).to.deep.equal({ lower: 0, upper: 15 });
+    });
+
+    it("should collapse to the price once every bit is decided", function () {
+      expect(clearingPriceBounds(intToBitsMSB(13, 4), 4)).to.deep.equal({ lower: 13, upper: 13 });
+    });
+
+    it("should not wrap at the 32-bit boundary", function () {
+      expect(clearingPriceBounds([1], 32)).to.deep.equal({ lower: 2 ** 31, upper: 2 ** 32 - 1 });
+      expect(clearingPriceBounds([], 53)).to.deep.equal({ lower: 0, upper: Number.MAX_SAFE_INTEGER });
+    });
+
+    it("should reject widths a number cannot hold exactly", function () {
+      expect(() => clearingPriceBounds([], 54)).to.throw("exceeds the 53 bits");
+    });
+  });
+
+  describe("intToBitsMSBChecked", function () {
+    it("should accept values that exactly fit", function () {
+      expect(intToBitsMSBChecked(15, 4)).to.deep.equal([1, 1, 1, 1]);
+      expect(intToBitsMSBChecked(0, 4)).to.deep.equal([0, 0, 0, 0]);
+    });
+
+    it("should reject values that overflow the width", function () {
+      expect(() => intToBitsMSBChecked(20, 4)).to.throw("does not fit in 4 bits");
+      expect(() => intToBitsMSBChecked(16, 4)).to.throw("does not fit in 4 bits");
+    });
+
+    it("should reject negative and non-integer values", function () {
+      expect(() => intToBitsMSBChecked(-1, 4)).to.throw("not a non-negative integer");
+      expect(() => intToBitsMSBChecked(1.5, 4)).to.throw("not a non-negative integer");
+    });
+  });
+
   describe("G1 point encode/decode roundtrip", function () {
     it("should encode and decode G_POINT", function () {
       const viem = pointToViem(G_POINT);
@@ -37,6 +149,76 @@ describe("Math Utils", function () {
       const recovered = viemToPoint(pointToViem(p));
       expect(recovered.equals(p)).to.be.true;
     });
+
+    it("should reject a point that is not on the curve", function () {
+      const zero = `0x${"0".repeat(64)}` as const;
+      const one  = `0x${"0".repeat(63)}1` as const;
+      expect(() => viemToPoint({ x_a: zero, x_b: one, y_a: zero, y_b: one })).to.throw("equation left != right");
+    });
+
+    it("should reject an on-curve point outside the prime-order subgroup", function () {
+      const p = nonSubgroupPoint();
+      expect(() => p.assertValidity()).to.throw("not in prime-order subgroup");
+      expect(() => viemToPoint(pointToViem(p))).to.throw("not in prime-order subgroup");
+    });
+  });
+
+  describe("wideBytesToScalar", function () {
+    it("should reject digests that are too short", function () {
+      expect(() => wideBytesToScalar(new Uint8Array(32))).to.throw("at least 48 bytes");
+    });
+
+    it("should be roughly uniform over [0, r)", function () {
+      const samples = 4000;
+      let below = 0;
+      for (let i = 0; i < samples; i++) {
+        const s = wideBytesToScalar(randomBytes(64));
+        expect(s < Fr.ORDER).to.be.true;
+        if (s < Fr.ORDER / 2n) below++;
+      }
+      // A 32-byte digest mod r would land below r/2 about 54.7% of the time
+      expect(Math.abs(below / samples - 0.5)).to.be.lessThan(0.03);
+    });
+  });
+
+  describe("deriveH", function () {
+    it("should reproduce H_POINT from its seed", function () {
+      expect(deriveH("SBRAC_H").equals(H_POINT)).to.be.true;
+    });
+
+    it("should give distinct subgroup points for distinct seeds", function () {
+      const h1 = deriveH("auction-1");
+      const h2 = deriveH("auction-2");
+      expect(h1.equals(h2)).to.be.false;
+      for (const h of [h1, h2]) {
+        expect(h.is0()).to.be.false;
+        expect(() => h.assertValidity()).to.not.throw();
+      }
+    });
+  });
+
+  describe("validateGenerators", function () {
+    it("should accept the expected G and H", function () {
+      expect(() => validateGenerators(G_POINT, H_POINT)).to.not.throw();
+    });
+
+    it("should reject the identity", function () {
+      expect(() => validateGenerators(G_POINT, G_ZERO)).to.throw("Generator is the identity");
+      expect(() => validateGenerators(G_ZERO, H_POINT)).to.throw("Generator is the identity");
+    });
+
+    it("should reject H == G", function () {
+      expect(() => validateGenerators(G_POINT, G_POINT)).to.throw("G and H must differ");
+    });
+
+    it("should reject H with a known discrete log w.r.t. G", function () {
+      const h = G_POINT.multiply(7n);
+      expect(() => validateGenerators(G_POINT, h)).to.throw("H is not the hash-derived generator");
+    });
+
+    it("should reject a point outside the prime-order subgroup", function () {
+      expect(() => validateGenerators(G_POINT, nonSubgroupPoint())).to.throw("not in prime-order subgroup");
+    });
   });
 
   describe("Pedersen commitment", function () {
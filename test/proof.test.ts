@@ -0,0 +1,232 @@
+import { expect } from "chai";
+import {
+  generateDLEqProof,
+  verifyDLEqProof,
+  generateOpeningProof,
+  verifyOpeningProof,
+  generateRangeProof,
+  verifyRangeProof,
+  generateEqualityProof,
+  verifyEqualityProof,
+  rerandomizeCommitment,
+  pedersenCommit,
+  scalarMul,
+  randomScalar,
+  seededScalars,
+  G_POINT,
+  H_POINT,
+  Fr,
+} from "../utils";
+
+describe("Proofs", function () {
+  describe("Chaum-Pedersen DLEq", function () {
+    const x = 123456789n;
+    const A = scalarMul(G_POINT, x);
+    const B = scalarMul(H_POINT, x);
+
+    it("should verify a proof for a known x", function () {
+      const proof = generateDLEqProof(G_POINT, H_POINT, A, B, x);
+      expect(verifyDLEqProof(G_POINT, H_POINT, A, B, proof)).to.be.true;
+    });
+
+    it("should reject a proof built with the wrong x", function () {
+      const proof = generateDLEqProof(G_POINT, H_POINT, A, B, x + 1n);
+      expect(verifyDLEqProof(G_POINT, H_POINT, A, B, proof)).to.be.false;
+    });
+
+    it("should reject when the discrete logs differ", function () {
+      const B2    = scalarMul(H_POINT, randomScalar());
+      const proof = generateDLEqProof(G_POINT, H_POINT, A, B2, x);
+      expect(verifyDLEqProof(G_POINT, H_POINT, A, B2, proof)).to.be.false;
+    });
+
+    it("should reject a tampered response", function () {
+      const proof = generateDLEqProof(G_POINT, H_POINT, A, B, x);
+      expect(verifyDLEqProof(G_POINT, H_POINT, A, B, { ...proof, z: proof.z + 1n })).to.be.false;
+    });
+
+    it("should bind the proof to the caller context", function () {
+      const idA   = new TextEncoder().encode("bidder-A");
+      const idB   = new TextEncoder().encode("bidder-B");
+      const proof = generateDLEqProof(G_POINT, H_POINT, A, B, x, idA);
+      expect(verifyDLEqProof(G_POINT, H_POINT, A, B, proof, idA)).to.be.true;
+      expect(verifyDLEqProof(G_POINT, H_POINT, A, B, proof, idB)).to.be.false;
+      expect(verifyDLEqProof(G_POINT, H_POINT, A, B, proof)).to.be.false;
+    });
+
+    it("should reject non-canonical encodings of a valid proof", function () {
+      const proof = generateDLEqProof(G_POINT, H_POINT, A, B, x);
+      expect(verifyDLEqProof(G_POINT, H_POINT, A, B, { ...proof, c: proof.c + Fr.ORDER })).to.be.false;
+      expect(verifyDLEqProof(G_POINT, H_POINT, A, B, { ...proof, z: proof.z + Fr.ORDER })).to.be.false;
+      expect(verifyDLEqProof(G_POINT, H_POINT, A, B, { ...proof, z: proof.z - Fr.ORDER })).to.be.false;
+    });
+  });
+
+  describe("Pedersen opening", function () {
+    const bid = 583n;
+    const r   = randomScalar();
+    const C   = pedersenCommit(bid, r);
+
+    it("should verify a proof built from the real opening", function () {
+      const proof = generateOpeningProof(C, bid, r);
+      expect(verifyOpeningProof(C, proof)).to.be.true;
+    });
+
+    it("should reject a proof built with the wrong randomness", function () {
+      const proof = generateOpeningProof(C, bid, r + 1n);
+      expect(verifyOpeningProof(C, proof)).to.be.false;
+    });
+
+    it("should reject a proof built with the wrong bid", function () {
+      const proof = generateOpeningProof(C, bid + 1n, r);
+      expect(verifyOpeningProof(C, proof)).to.be.false;
+    });
+
+    it("should reject a proof presented for another commitment", function () {
+      const proof = generateOpeningProof(C, bid, r);
+      expect(verifyOpeningProof(pedersenCommit(bid, randomScalar()), proof)).to.be.false;
+    });
+
+    it("should reject non-canonical encodings of a valid proof", function () {
+      const proof = generateOpeningProof(C, bid, r);
+      expect(verifyOpeningProof(C, { ...proof, c: proof.c + Fr.ORDER })).to.be.false;
+      expect(verifyOpeningProof(C, { ...proof, z1: proof.z1 + Fr.ORDER })).to.be.false;
+    });
+  });
+
+  describe("Range proof", function () {
+    const bid = 583n;
+    const r   = randomScalar();
+    const C   = pedersenCommit(bid, r);
+
+    it("should verify an in-range bid", function () {
+      const proof = generateRangeProof(C, bid, r, 16);
+      expect(verifyRangeProof(C, proof, 16)).to.be.true;
+    });
+
+    it("should refuse to prove an out-of-range bid", function () {
+      expect(() => generateRangeProof(C, bid, r, 8)).to.throw("does not fit in 8 bits");
+    });
+
+    it("should refuse bit lengths whose weights wrap mod r", function () {
+      const big = Fr.ORDER.toString(2).length; // smallest l with 2^l > r
+      expect(() => generateRangeProof(C, bid, r, 0)).to.throw("must satisfy");
+      expect(() => generateRangeProof(C, bid, r, big)).to.throw("must satisfy");
+
+      const proof = generateRangeProof(C, bid, r, 16);
+      const pad   = <T>(xs: T[], l: number): T[] => Array.from({ length: l }, (_, k) => xs[k % xs.length]);
+      for (const l of [0, big]) {
+        const forged = { ...proof, bitCommits: pad(proof.bitCommits, l), bitProofs: pad(proof.bitProofs, l) };
+        expect(verifyRangeProof(C, forged, l)).to.be.false;
+      }
+    });
+
+    it("should reject a proof checked against a different bit length", function () {
+      const proof = generateRangeProof(C, bid, r, 16);
+      expect(verifyRangeProof(C, proof, 12)).to.be.false;
+    });
+
+    it("should reject a proof presented for another commitment", function () {
+      const proof = generateRangeProof(C, bid, r, 16);
+      expect(verifyRangeProof(pedersenCommit(bid + 1n, r), proof, 16)).to.be.false;
+    });
+
+    it("should reject a bit commitment that is neither 0 nor 1", function () {
+      const proof = generateRangeProof(C, bid, r, 16);
+      const bitCommits = [...proof.bitCommits];
+      bitCommits[3] = bitCommits[3].add(G_POINT);
+      expect(verifyRangeProof(C, { ...proof, bitCommits }, 16)).to.be.false;
+    });
+
+    it("should reject non-canonical scalars in bit and sum proofs", function () {
+      const proof = generateRangeProof(C, bid, r, 16);
+      const bitProofs = [...proof.bitProofs];
+      bitProofs[0] = { ...bitProofs[0], c0: bitProofs[0].c0 + Fr.ORDER };
+      expect(verifyRangeProof(C, { ...proof, bitProofs }, 16)).to.be.false;
+      const sumProof = { ...proof.sumProof, z: proof.sumProof.z + Fr.ORDER };
+      expect(verifyRangeProof(C, { ...proof, sumProof }, 16)).to.be.false;
+    });
+
+    it("should reject a bit proof whose challenge shares were both chosen by the prover", function () {
+      const proof = generateRangeProof(C, bid, r, 16);
+
+      // Simulating both OR branches lets the prover pick c0, c1, z0, z1 freely:
+      // R_i = z_i*H + c_i*Y_i then holds for both i by construction, but
+      // c0 + c1 no longer equals the hash of R0, R1.
+      const simulate = () => ({ c0: randomScalar(), c1: randomScalar(), z0: randomScalar(), z1: randomScalar() });
+
+      const bitProofs = [...proof.bitProofs];
+      bitProofs[0] = simulate();
+      expect(verifyRangeProof(C, { ...proof, bitProofs }, 16)).to.be.false;
+
+      // Shifting weight between bits keeps the sum proof valid (3*1 + 0*2 == 1*1 + 1*2)
+      // while bit 0 now commits to 3. Both modified bits get simulated proofs,
+      // so only the challenge check can reject them.
+      const bitCommits = [...proof.bitCommits];
+      bitCommits[0] = bitCommits[0].add(G_POINT).add(G_POINT);
+      bitCommits[1] = bitCommits[1].subtract(G_POINT);
+      bitProofs[1] = simulate();
+      expect(verifyRangeProof(C, { ...proof, bitCommits, bitProofs }, 16)).to.be.false;
+    });
+  });
+
+  describe("Commitment re-randomization", function () {
+    const bid = 583n;
+    const r   = randomScalar();
+    const C   = pedersenCommit(bid, r);
+
+    it("should commit to the same bid under fresh randomness", function () {
+      const { commitment, rPrime } = rerandomizeCommitment(C);
+      expect(commitment.equals(C)).to.be.false;
+      expect(commitment.equals(pedersenCommit(bid, r + rPrime))).to.be.true;
+    });
+
+    it("should prove a genuine re-randomization", function () {
+      const { commitment, rPrime } = rerandomizeCommitment(C);
+      const proof = generateEqualityProof(C, commitment, rPrime);
+      expect(verifyEqualityProof(C, commitment, proof)).to.be.true;
+    });
+
+    it("should reject a commitment to a different bid", function () {
+      const rPrime = randomScalar();
+      const other  = pedersenCommit(bid + 1n, r + rPrime);
+      const proof  = generateEqualityProof(C, other, rPrime);
+      expect(verifyEqualityProof(C, other, proof)).to.be.false;
+    });
+  });
+
+  describe("Seeded nonces", function () {
+    const x = 123456789n;
+    const A = scalarMul(G_POINT, x);
+    const B = scalarMul(H_POINT, x);
+    const r = 987654321n;
+    const C = pedersenCommit(583n, r);
+    const ctx = new Uint8Array();
+
+    it("should give identical DLEq and opening proofs for the same seed", function () {
+      const p1 = generateDLEqProof(G_POINT, H_POINT, A, B, x, ctx, seededScalars("seed"));
+      const p2 = generateDLEqProof(G_POINT, H_POINT, A, B, x, ctx, seededScalars("seed"));
+      expect(p1).to.deep.equal(p2);
+      expect(verifyDLEqProof(G_POINT, H_POINT, A, B, p1)).to.be.true;
+
+      const o1 = generateOpeningProof(C, 583n, r, ctx, seededScalars("seed"));
+      const o2 = generateOpeningProof(C, 583n, r, ctx, seededScalars("seed"));
+      expect(o1).to.deep.equal(o2);
+    });
+
+    it("should give identical range proofs for the same seed", function () {
+      const p1 = generateRangeProof(C, 583n, r, 16, ctx, seededScalars("seed"));
+      const p2 = generateRangeProof(C, 583n, r, 16, ctx, seededScalars("seed"));
+      expect(p1.bitCommits.map((p) => p.toHex(true))).to.deep.equal(p2.bitCommits.map((p) => p.toHex(true)));
+      expect(p1.bitProofs).to.deep.equal(p2.bitProofs);
+      expect(p1.sumProof).to.deep.equal(p2.sumProof);
+      expect(verifyRangeProof(C, p1, 16)).to.be.true;
+    });
+
+    it("should give different proofs for different seeds", function () {
+      const p1 = generateDLEqProof(G_POINT, H_POINT, A, B, x, ctx, seededScalars("seed-1"));
+      const p2 = generateDLEqProof(G_POINT, H_POINT, A, B, x, ctx, seededScalars("seed-2"));
+      expect(p1.z).to.not.equal(p2.z);
+    });
+  });
+});
@@ -1,4 +1,4 @@
-import { G_POINT, H_POINT, G_ZERO, N, L } from "./constants";
+import { G_POINT, H_POINT, G_ZERO, L } from "./constants";
 import {
   G1Point,
   G1PointViem,
@@ -9,18 +9,28 @@ import {
   pedersenCommit,
   pointToViem,
   viemToPoint,
-  intToBitsMSB,
+  intToBitsMSBChecked,
 } from "./math";
 
+/**
+ * active:       still in contention
+ * eliminated:   lost the reveal at some bit position
+ * disqualified: excluded for a protocol violation (e.g. invalid proof or keys)
+ */
+export type BidderStatus = "active" | "eliminated" | "disqualified";
+
 export class Bidder {
   id: number;
-  bid: number;
+  bid!: number;
+  /** Number of bit positions; must match the contract's BIT_LENGTH. */
+  bitLength: number;
   /** MSB-first binary representation (bidBinary[0] = MSB). */
-  bidBinary: number[];
-  salt: bigint;
-  isLost = false;
+  bidBinary!: number[];
+  salt!: bigint;
 
-  private _commitment: G1Point;
+  private _status: BidderStatus = "active";
+  private _reason = "";
+  private _commitment!: G1Point;
   private _privX: bigint[]   = [];
   private _pubX:  G1Point[]  = [];
   private _privS: bigint[]   = [];
@@ -28,14 +38,33 @@ export class Bidder {
   private _bitZeroCommits: G1Point[] = [];
   private _bitOneCommits:  G1Point[] = [];
 
-  constructor(id: number, bid: number) {
+  constructor(id: number, bid: number, bitLength: number = L) {
     this.id        = id;
+    this.bitLength = bitLength;
+    this.reset(bid);
+  }
+
+  /**
+   * Reuse this bidder for a new auction round: draws a fresh salt and fresh
+   * AV-net keys for `bid`, and clears bit commitments and status.
+   * The id and bit length are kept.
+   */
+  reset(bid: number) {
     this.bid       = bid;
-    this.bidBinary = intToBitsMSB(bid, L);
+    this.bidBinary = intToBitsMSBChecked(bid, this.bitLength);
     this.salt      = randomScalar();
     this._commitment = pedersenCommit(BigInt(bid), this.salt);
+    this._status   = "active";
+    this._reason   = "";
+
+    this._privX = [];
+    this._pubX  = [];
+    this._privS = [];
+    this._pubS  = [];
+    this._bitZeroCommits = [];
+    this._bitOneCommits  = [];
 
-    for (let j = 0; j < L; j++) {
+    for (let j = 0; j < this.bitLength; j++) {
       const x = randomScalar();
       this._privX.push(x);
       this._pubX.push(scalarMul(G_POINT, x));
@@ -46,6 +75,33 @@ export class Bidder {
     }
   }
 
+  // ─── Status ────────────────────────────────────────────────────────────────
+
+  // Read-only: status changes only through eliminate, disqualify and reset.
+  get status(): BidderStatus { return this._status; }
+  /** Human-readable reason for the current status; empty while active. */
+  get reason(): string { return this._reason; }
+
+  /** True once the bidder can no longer win (eliminated or disqualified). */
+  get isLost(): boolean { return this._status !== "active"; }
+
+  /** Mark the bidder as having lost the reveal at bit position j (no-op if already out). */
+  eliminate(j: number) {
+    if (this._status !== "active") return;
+    this._status = "eliminated";
+    this._reason = `Eliminated at bit ${j}`;
+  }
+
+  /**
+   * Exclude the bidder for a protocol violation; overrides elimination.
+   * Nothing in utils calls this: proofs are checked by the caller, which
+   * disqualifies the offending bidder.
+   */
+  disqualify(reason: string) {
+    this._status = "disqualified";
+    this._reason = reason;
+  }
+
   // ─── Viem-ready getters (for contract calls) ───────────────────────────────
 
   get commitment(): G1PointViem { return pointToViem(this._commitment); }
@@ -61,22 +117,61 @@ export class Bidder {
    * Compute per-bit cryptograms after all bidders have joined.
    * @param allPubXs  2D array from contract.getPublicXs():
    *                  allPubXs[i][j] = bidder i's X key for bit position j.
+   * @param n         Expected number of bidders (the contract's N). getPublicXs()
+   *                  only returns the bidders that have joined so far, and tally
+   *                  keys built over a partial list never cancel.
+   *
+   * Since sum_i x_ij * T_i = 0, the bit-1 encodings of all bidders cancel out.
+   * A bidder who has already lost must therefore always send the bit-1 encoding:
+   * it is neutral in the sum and can never flip a lower position's decision.
    */
-  computeBitCommitments(allPubXs: readonly (readonly G1PointViem[])[]) {
+  computeBitCommitments(allPubXs: readonly (readonly G1PointViem[])[], n: number) {
+    if (allPubXs.length !== n) {
+      throw new Error(`Expected public keys from ${n} bidders, got ${allPubXs.length}`);
+    }
+    allPubXs.forEach((row, i) => {
+      if (row.length !== this.bitLength) {
+        throw new Error(`Bit length mismatch: bidder ${i} has ${row.length} public keys, expected ${this.bitLength}`);
+      }
+    });
+
+    // Identical X keys would make the tally keys degenerate and link the two bidders
+    for (let j = 0; j < this.bitLength; j++) {
+      const seen = new Map<string, number>();
+      allPubXs.forEach((row, i) => {
+        const { x_a, x_b, y_a, y_b } = row[j];
+        const key = `${x_a}${x_b}${y_a}${y_b}`.toLowerCase();
+        const prev = seen.get(key);
+        if (prev !== undefined) {
+          throw new Error(`Duplicate public key: bidders ${prev} and ${i} share X at bit ${j}`);
+        }
+        seen.set(key, i);
+      });
+    }
+
     this._bitZeroCommits = [];
     this._bitOneCommits  = [];
 
     const points = allPubXs.map((row) => row.map(viemToPoint));
 
-    for (let j = 0; j < L; j++) {
+    // T_i splits the list at this.id, so our own keys must sit at that index
+    const own = points[this.id];
+    if (!own || !own.every((p, j) => p.equals(this._pubX[j]))) {
+      throw new Error(`Bidder ${this.id}'s public keys are not at index ${this.id}`);
+    }
+
+    for (let j = 0; j < this.bitLength; j++) {
       // T_i = (∑_{k<i} X_k[j]) - (∑_{k>i} X_k[j])
       let pre:  G1Point = G_ZERO;
       let post: G1Point = G_ZERO;
 
       for (let k = 0; k < this.id; k++)      pre  = pointAdd(pre,  points[k][j]);
-      for (let k = this.id + 1; k < N; k++)  post = pointAdd(post, points[k][j]);
+      for (let k = this.id + 1; k < n; k++)  post = pointAdd(post, points[k][j]);
 
       const Ti = pointSub(pre, post);
+      // An identity T_i makes both encodings the identity, so this bidder
+      // would read as "bit 1" regardless of their bid
+      if (Ti.is0()) throw new Error(`Degenerate tally key: T_${this.id} is the identity at bit ${j}`);
 
       // bit=0 (has 0 at this position): s_j * T_i
       // bit=1 (has 1 at this position, or already lost): x_j * T_i
@@ -85,3 +180,21 @@ export class Bidder {
     }
   }
 }
+
+// ─── Winner classification ────────────────────────────────────────────────────
+
+/**
+ * Reverse auction: the clearing price is the lowest bid, so a bidder wins
+ * iff their bid equals it exactly. Only such a bidder can open their
+ * commitment in Auction.declareWinner. Bids above the price lose.
+ */
+export function isWinner(bid: number, clearingPrice: number): boolean {
+  return bid === clearingPrice;
+}
+
+/** Indices of all bids that win at the given clearing price (more than one on a tie). */
+export function winners(bids: number[], clearingPrice: number): number[] {
+  const ids: number[] = [];
+  bids.forEach((bid, i) => { if (isWinner(bid, clearingPrice)) ids.push(i); });
+  return ids;
+}
@@ -1,3 +1,4 @@
 export * from "./constants";
 export * from "./math";
 export * from "./bidder";
+export * from "./proof";
@@ -1,3 +1,4 @@
+import { createHash } from "crypto";
 import { bls12_381 } from "@noble/curves/bls12-381.js";
 import { G_POINT, H_POINT, G_ZERO, Fr } from "./constants";
 
@@ -24,7 +25,12 @@ export type G1PointViem = {
 
 // ─── Bit helpers ─────────────────────────────────────────────────────────────
 
-/** Convert integer to bit array, LSB first. */
+/**
+ * Convert integer to bit array, LSB first. Uses 32-bit shifts: n is truncated to
+ * its low 32 bits, and for width > 32 the shift count wraps mod 32, so bit i
+ * repeats bit i - 32. Bits at or above `width` are dropped without warning.
+ * Use bigIntToBits for wider values.
+ */
 export function intToBits(n: number, width: number): number[] {
   const bits: number[] = [];
   for (let i = 0; i < width; i++) bits.push((n >> i) & 1);
@@ -38,11 +44,69 @@ export function bitsToInt(bits: number[]): number {
   return n;
 }
 
-/** Convert integer to bit array, MSB first (used by auction protocol). */
+/** Convert bigint to bit array, LSB first. Use for bids wider than 31 bits. */
+export function bigIntToBits(n: bigint, width: number): number[] {
+  const bits: number[] = [];
+  for (let i = 0; i < width; i++) bits.push(Number((n >> BigInt(i)) & 1n));
+  return bits;
+}
+
+/** Convert bit array (LSB first) back to bigint. */
+export function bitsToBigInt(bits: number[]): bigint {
+  let n = 0n;
+  for (let i = 0; i < bits.length; i++) n |= BigInt(bits[i]) << BigInt(i);
+  return n;
+}
+
+/**
+ * Convert integer to bit array, MSB first. Does not truncate: if n >= 2^width the
+ * result has more than `width` entries. Negative or non-integer n gives garbage.
+ * Use intToBitsMSBChecked for untrusted input.
+ */
 export function intToBitsMSB(n: number, width: number): number[] {
   return n.toString(2).padStart(width, "0").split("").map(Number);
 }
 
+/** Bit order of a bit array: "lsb" = bits[0] is the LSB, "msb" = bits[0] is the MSB. */
+export type BitOrder = "lsb" | "msb";
+
+/** Convert integer to bit array in the given order. Not limited to 32 bits; throws if n does not fit. */
+export function intToBitsOrder(n: number, width: number, order: BitOrder): number[] {
+  if (!Number.isSafeInteger(n) || n < 0) throw new Error(`Value ${n} is not a non-negative integer`);
+  if (BigInt(n) >> BigInt(width) !== 0n) throw new Error(`Value ${n} does not fit in ${width} bits`);
+  const bits = bigIntToBits(BigInt(n), width);
+  return order === "lsb" ? bits : bits.reverse();
+}
+
+/** Convert bit array in the given order back to integer. Throws above Number.MAX_SAFE_INTEGER. */
+export function bitsToIntOrder(bits: number[], order: BitOrder): number {
+  const n = bitsToBigInt(order === "lsb" ? bits : [...bits].reverse());
+  if (n > BigInt(Number.MAX_SAFE_INTEGER)) throw new Error(`Value ${n} exceeds Number.MAX_SAFE_INTEGER`);
+  return Number(n);
+}
+
+/**
+ * Range [lower, upper] the clearing price must fall in, given the MSB-first
+ * bits decided so far (e.g. a prefix of the contract's clearingPriceBits).
+ */
+export function clearingPriceBounds(prefixBits: number[], width: number): { lower: number; upper: number } {
+  if (width > 53) throw new Error(`Width ${width} exceeds the 53 bits a number can hold exactly`);
+  if (prefixBits.length > width) throw new Error(`Prefix of ${prefixBits.length} bits exceeds width ${width}`);
+  const rest  = width - prefixBits.length;
+  const lower = bitsToIntOrder([...prefixBits, ...new Array(rest).fill(0)], "msb");
+  return { lower, upper: lower + 2 ** rest - 1 };
+}
+
+/**
+ * Like intToBitsMSB, but throws if n is negative, not an integer,
+ * or does not fit in `width` bits.
+ */
+export function intToBitsMSBChecked(n: number, width: number): number[] {
+  if (!Number.isSafeInteger(n) || n < 0) throw new Error(`Value ${n} is not a non-negative integer`);
+  if (BigInt(n) >> BigInt(width) !== 0n) throw new Error(`Value ${n} does not fit in ${width} bits`);
+  return intToBitsMSB(n, width);
+}
+
 // ─── Scalar helpers ───────────────────────────────────────────────────────────
 
 /** Cryptographically random scalar in [1, r-1]. */
@@ -53,6 +117,31 @@ export function randomScalar(): bigint {
   return Fr.create(BigInt("0x" + hex));
 }
 
+/**
+ * Deterministic stand-in for randomScalar: the k-th call returns
+ * SHA-512(seed || k) reduced mod r. For reproducible tests only.
+ */
+export function seededScalars(seed: string): () => bigint {
+  let counter = 0;
+  return () => {
+    const ctr = new Uint8Array(4);
+    new DataView(ctr.buffer).setUint32(0, counter++);
+    return wideBytesToScalar(createHash("sha512").update(seed).update(ctr).digest());
+  };
+}
+
+/**
+ * Reduce a wide hash digest to a scalar mod r.
+ * Needs at least 48 bytes: a 32-byte digest mod r (~2^255) is noticeably biased,
+ * a 64-byte one leaves a bias below 2^-256.
+ */
+export function wideBytesToScalar(bytes: Uint8Array): bigint {
+  if (bytes.length < 48) throw new Error(`Need at least 48 bytes, got ${bytes.length}`);
+  let hex = "";
+  for (const b of bytes) hex += b.toString(16).padStart(2, "0");
+  return Fr.create(BigInt("0x" + hex));
+}
+
 // ─── EC point operations ──────────────────────────────────────────────────────
 
 export const scalarMul = (p: G1Point, s: bigint): G1Point => p.multiply(Fr.create(s));
@@ -63,11 +152,42 @@ export const pointSub = (p: G1Point, q: G1Point): G1Point => p.add(q.negate());
 
 export const pointNeg = (p: G1Point): G1Point => p.negate();
 
+/**
+ * Derive a nothing-up-my-sleeve generator from a seed via hash-to-curve.
+ * The result lies in the prime-order subgroup and has no known discrete log
+ * w.r.t. G. deriveH("SBRAC_H") reproduces H_POINT.
+ */
+export function deriveH(seed: string): G1Point {
+  return bls12_381.G1.hashToCurve(new TextEncoder().encode(seed));
+}
+
+/**
+ * Check generators read back from a deployed auction. The contract only rejects
+ * the identity and G == H; whether H = k*G for a known k cannot be tested, so
+ * both points must also match the expected G_POINT and H_POINT.
+ */
+export function validateGenerators(g: G1Point, h: G1Point): void {
+  if (g.is0() || h.is0()) throw new Error("Generator is the identity");
+  g.assertValidity();
+  h.assertValidity();
+  if (g.equals(h)) throw new Error("G and H must differ");
+  if (!g.equals(G_POINT)) throw new Error("G is not the standard generator");
+  if (!h.equals(H_POINT)) throw new Error("H is not the hash-derived generator");
+}
+
 /** Pedersen commitment: bid*G + r*H */
 export function pedersenCommit(bid: bigint, r: bigint): G1Point {
   return scalarMul(G_POINT, bid).add(scalarMul(H_POINT, r));
 }
 
+/**
+ * Re-randomize a Pedersen commitment: C' = C + r'*H commits to the same bid
+ * under randomness r + r'. A fresh r' is drawn if none is given.
+ */
+export function rerandomizeCommitment(C: G1Point, rPrime: bigint = randomScalar()): { commitment: G1Point; rPrime: bigint } {
+  return { commitment: C.add(scalarMul(H_POINT, rPrime)), rPrime };
+}
+
 // ─── Encoding: G1Point ↔ Viem ────────────────────────────────────────────────
 
 /**
@@ -106,6 +226,8 @@ function bytes32PairToFp(hi: `0x${string}`, lo: `0x${string}`): bigint {
  * Convert a Viem G1Point back to a G1 projective point.
  * Accepts either a named object {x_a,x_b,y_a,y_b} (from explicit function returns)
  * or a plain 4-element array [x_a,x_b,y_a,y_b] (from mapping auto-getters).
+ * Throws if the point is not on the curve or not in the prime-order subgroup,
+ * since small-subgroup points would break the AV-net and proof soundness.
  */
 export function viemToPoint(v: G1PointViem | readonly [`0x${string}`, `0x${string}`, `0x${string}`, `0x${string}`]): G1Point {
   const isArr = Array.isArray(v);
@@ -115,5 +237,7 @@ export function viemToPoint(v: G1PointViem | readonly [`0x${string}`, `0x${strin
   const y_b = isArr ? (v as any)[3] : (v as G1PointViem).y_b;
   const x = bytes32PairToFp(x_a, x_b);
   const y = bytes32PairToFp(y_a, y_b);
-  return bls12_381.G1.Point.fromAffine({ x, y });
+  const p = bls12_381.G1.Point.fromAffine({ x, y });
+  p.assertValidity();
+  return p;
 }
@@ -0,0 +1,285 @@
+import { createHash } from "crypto";
+import { G_POINT, H_POINT, G_ZERO, Fr } from "./constants";
+import { G1Point, randomScalar, scalarMul, pointSub, bigIntToBits, wideBytesToScalar } from "./math";
+
+// ─── Types ───────────────────────────────────────────────────────────────────
+
+/**
+ * Chaum-Pedersen proof of equality of discrete logs.
+ * Proves knowledge of x such that A = x*g and B = x*h without revealing x.
+ */
+export type DLEqProof = {
+  c: bigint; // Fiat-Shamir challenge
+  z: bigint; // response z = k - c*x mod r
+};
+
+/**
+ * Proof of knowledge of the opening (bid, r) of a Pedersen commitment C = bid*G + r*H.
+ */
+export type OpeningProof = {
+  c:  bigint; // Fiat-Shamir challenge
+  z1: bigint; // response for bid: k1 - c*bid mod r
+  z2: bigint; // response for r:   k2 - c*r   mod r
+};
+
+/** Schnorr proof of knowledge of w such that Y = w*H. */
+export type SchnorrProof = {
+  c: bigint; // Fiat-Shamir challenge
+  z: bigint; // response z = k - c*w mod r
+};
+
+/** Proof that two Pedersen commitments open to the same bid (they differ by a multiple of H). */
+export type EqualityProof = SchnorrProof;
+
+/**
+ * CDS OR-proof that a bit commitment C_k = b*G + w*H has b in {0, 1}.
+ * Branch 0 proves C_k = w*H, branch 1 proves C_k - G = w*H; c0 + c1 = challenge.
+ */
+export type BitProof = {
+  c0: bigint;
+  c1: bigint;
+  z0: bigint;
+  z1: bigint;
+};
+
+/**
+ * Proof that a Pedersen commitment C opens to a bid in [0, 2^l).
+ * bitCommits[k] commits to bit k (LSB first); sumProof shows that
+ * C - sum_k 2^k * bitCommits[k] is a multiple of H alone.
+ */
+export type RangeProof = {
+  bitCommits: G1Point[];
+  bitProofs:  BitProof[];
+  sumProof:   SchnorrProof;
+};
+
+// ─── Fiat-Shamir helpers ──────────────────────────────────────────────────────
+
+/** Scalar multiplication that maps a zero scalar to the identity instead of throwing. */
+const mulOrZero = (p: G1Point, s: bigint): G1Point =>
+  Fr.create(s) === 0n ? G_ZERO : scalarMul(p, s);
+
+/**
+ * True iff every scalar is canonical, i.e. in [0, r). Verifiers reject anything
+ * else, since c + r or z + r would otherwise verify as a second encoding.
+ */
+const canonical = (...scalars: bigint[]): boolean =>
+  scalars.every((s) => s >= 0n && s < Fr.ORDER);
+
+/**
+ * Hash a domain tag, caller context and a list of G1 points to a scalar mod r.
+ * The context is length-prefixed (4-byte big-endian); points are hashed in
+ * 48-byte compressed form, so every field is unambiguous. SHA-512 is used so
+ * the reduction mod r is uniform (see wideBytesToScalar).
+ */
+function challenge(tag: string, context: Uint8Array, points: G1Point[]): bigint {
+  const len = new Uint8Array(4);
+  new DataView(len.buffer).setUint32(0, context.length);
+
+  const hasher = createHash("sha512");
+  hasher.update(tag);
+  hasher.update(Uint8Array.of(0));
+  hasher.update(len);
+  hasher.update(context);
+  for (const p of points) hasher.update(p.toBytes(true));
+  return wideBytesToScalar(hasher.digest());
+}
+
+// ─── Chaum-Pedersen (DLEq) ────────────────────────────────────────────────────
+
+/**
+ * Prove log_g(A) == log_h(B) == x.
+ * @param g, h  Bases.
+ * @param A, B  A = x*g, B = x*h.
+ * @param x     The shared discrete log (secret).
+ * @param context  Optional bytes bound into the challenge (e.g. bidder identity);
+ *                 the verifier must supply the same value.
+ * @param nonce    Source of proof nonces; pass seededScalars(...) for reproducible proofs.
+ */
+export function generateDLEqProof(
+  g: G1Point, h: G1Point, A: G1Point, B: G1Point, x: bigint,
+  context: Uint8Array = new Uint8Array(),
+  nonce: () => bigint = randomScalar,
+): DLEqProof {
+  const k  = nonce();
+  const R1 = scalarMul(g, k);
+  const R2 = scalarMul(h, k);
+  const c  = challenge("SBRAC_DLEQ", context, [g, h, A, B, R1, R2]);
+  const z  = Fr.sub(k, Fr.mul(c, Fr.create(x)));
+  return { c, z };
+}
+
+/** Verify a DLEqProof: recompute R1 = z*g + c*A, R2 = z*h + c*B and check the challenge. */
+export function verifyDLEqProof(
+  g: G1Point, h: G1Point, A: G1Point, B: G1Point, proof: DLEqProof,
+  context: Uint8Array = new Uint8Array(),
+): boolean {
+  if (!canonical(proof.c, proof.z)) return false;
+  const R1 = mulOrZero(g, proof.z).add(mulOrZero(A, proof.c));
+  const R2 = mulOrZero(h, proof.z).add(mulOrZero(B, proof.c));
+  return challenge("SBRAC_DLEQ", context, [g, h, A, B, R1, R2]) === proof.c;
+}
+
+// ─── Pedersen commitment opening ──────────────────────────────────────────────
+
+/**
+ * Prove knowledge of (bid, r) such that C = bid*G + r*H, without revealing them.
+ * @param context, nonce  See generateDLEqProof.
+ */
+export function generateOpeningProof(
+  C: G1Point, bid: bigint, r: bigint,
+  context: Uint8Array = new Uint8Array(),
+  nonce: () => bigint = randomScalar,
+): OpeningProof {
+  const k1 = nonce();
+  const k2 = nonce();
+  const R  = scalarMul(G_POINT, k1).add(scalarMul(H_POINT, k2));
+  const c  = challenge("SBRAC_OPEN", context, [G_POINT, H_POINT, C, R]);
+  const z1 = Fr.sub(k1, Fr.mul(c, Fr.create(bid)));
+  const z2 = Fr.sub(k2, Fr.mul(c, Fr.create(r)));
+  return { c, z1, z2 };
+}
+
+/** Verify an OpeningProof: recompute R = z1*G + z2*H + c*C and check the challenge. */
+export function verifyOpeningProof(
+  C: G1Point, proof: OpeningProof,
+  context: Uint8Array = new Uint8Array(),
+): boolean {
+  if (!canonical(proof.c, proof.z1, proof.z2)) return false;
+  const R = mulOrZero(G_POINT, proof.z1)
+    .add(mulOrZero(H_POINT, proof.z2))
+    .add(mulOrZero(C, proof.c));
+  return challenge("SBRAC_OPEN", context, [G_POINT, H_POINT, C, R]) === proof.c;
+}
+
+// ─── Range proof ──────────────────────────────────────────────────────────────
+
+/** Schnorr proof of knowledge of w with Y = w*H; `bound` points are hashed into the challenge. */
+function proveDlogH(
+  tag: string, Y: G1Point, w: bigint, bound: G1Point[], context: Uint8Array, nonce: () => bigint,
+): SchnorrProof {
+  const k = nonce();
+  const R = scalarMul(H_POINT, k);
+  const c = challenge(tag, context, [H_POINT, ...bound, Y, R]);
+  return { c, z: Fr.sub(k, Fr.mul(c, Fr.create(w))) };
+}
+
+function verifyDlogH(tag: string, Y: G1Point, proof: SchnorrProof, bound: G1Point[], context: Uint8Array): boolean {
+  if (!canonical(proof.c, proof.z)) return false;
+  const R = mulOrZero(H_POINT, proof.z).add(mulOrZero(Y, proof.c));
+  return challenge(tag, context, [H_POINT, ...bound, Y, R]) === proof.c;
+}
+
+/** OR-proof that Ck = w*H (bit = 0) or Ck - G = w*H (bit = 1). */
+function proveBit(C: G1Point, Ck: G1Point, bit: number, w: bigint, context: Uint8Array, nonce: () => bigint): BitProof {
+  const Y = [Ck, pointSub(Ck, G_POINT)];
+  const R: G1Point[] = [G_ZERO, G_ZERO];
+  const c: bigint[]  = [0n, 0n];
+  const z: bigint[]  = [0n, 0n];
+
+  // Simulate the branch we cannot prove
+  const fake = 1 - bit;
+  c[fake] = nonce();
+  z[fake] = nonce();
+  R[fake] = scalarMul(H_POINT, z[fake]).add(scalarMul(Y[fake], c[fake]));
+
+  // Prove the real branch with the remaining share of the challenge
+  const k = nonce();
+  R[bit] = scalarMul(H_POINT, k);
+  const total = challenge("SBRAC_BIT", context, [G_POINT, H_POINT, C, Ck, R[0], R[1]]);
+  c[bit] = Fr.sub(total, c[fake]);
+  z[bit] = Fr.sub(k, Fr.mul(c[bit], Fr.create(w)));
+
+  return { c0: c[0], c1: c[1], z0: z[0], z1: z[1] };
+}
+
+function verifyBit(C: G1Point, Ck: G1Point, proof: BitProof, context: Uint8Array): boolean {
+  if (!canonical(proof.c0, proof.c1, proof.z0, proof.z1)) return false;
+  const R0 = mulOrZero(H_POINT, proof.z0).add(mulOrZero(Ck, proof.c0));
+  const R1 = mulOrZero(H_POINT, proof.z1).add(mulOrZero(pointSub(Ck, G_POINT), proof.c1));
+  const total = challenge("SBRAC_BIT", context, [G_POINT, H_POINT, C, Ck, R0, R1]);
+  return Fr.add(proof.c0, proof.c1) === total;
+}
+
+/** sum_k 2^k * points[k] */
+function weightedSum(points: G1Point[]): G1Point {
+  let acc = G_ZERO;
+  for (let k = 0; k < points.length; k++) acc = acc.add(mulOrZero(points[k], 1n << BigInt(k)));
+  return acc;
+}
+
+/**
+ * A bit length the range proof is sound for: with 2^l >= r the weights 2^k
+ * wrap mod r and every scalar becomes representable.
+ */
+const validBitLength = (l: number): boolean =>
+  Number.isInteger(l) && l >= 1 && (1n << BigInt(l)) < Fr.ORDER;
+
+/**
+ * Prove that C = bid*G + r*H commits to a bid in [0, 2^l).
+ * Throws if the bid is out of range, since no valid proof exists.
+ * The bit-commitment randomness is drawn from `nonce` as well.
+ */
+export function generateRangeProof(
+  C: G1Point, bid: bigint, r: bigint, l: number,
+  context: Uint8Array = new Uint8Array(),
+  nonce: () => bigint = randomScalar,
+): RangeProof {
+  if (!validBitLength(l)) throw new Error(`Bit length ${l} must satisfy 1 <= l and 2^l < r`);
+  if (bid < 0n || bid >> BigInt(l) !== 0n) throw new Error(`Bid ${bid} does not fit in ${l} bits`);
+
+  const bits = bigIntToBits(bid, l);
+  const bitCommits: G1Point[] = [];
+  const bitProofs:  BitProof[] = [];
+  let rBits = 0n;
+
+  for (let k = 0; k < l; k++) {
+    const w  = nonce();
+    const Ck = bits[k] === 1 ? G_POINT.add(scalarMul(H_POINT, w)) : scalarMul(H_POINT, w);
+    bitCommits.push(Ck);
+    bitProofs.push(proveBit(C, Ck, bits[k], w, context, nonce));
+    rBits = Fr.add(rBits, Fr.mul(w, Fr.create(1n << BigInt(k))));
+  }
+
+  const D = pointSub(C, weightedSum(bitCommits));
+  const sumProof = proveDlogH("SBRAC_RANGE_SUM", D, Fr.sub(Fr.create(r), rBits), [C], context, nonce);
+  return { bitCommits, bitProofs, sumProof };
+}
+
+/** Verify a RangeProof for C over exactly l bits. */
+export function verifyRangeProof(
+  C: G1Point, proof: RangeProof, l: number,
+  context: Uint8Array = new Uint8Array(),
+): boolean {
+  if (!validBitLength(l)) return false;
+  if (proof.bitCommits.length !== l || proof.bitProofs.length !== l) return false;
+
+  for (let k = 0; k < l; k++) {
+    if (!verifyBit(C, proof.bitCommits[k], proof.bitProofs[k], context)) return false;
+  }
+
+  const D = pointSub(C, weightedSum(proof.bitCommits));
+  return verifyDlogH("SBRAC_RANGE_SUM", D, proof.sumProof, [C], context);
+}
+
+// ─── Commitment equality ──────────────────────────────────────────────────────
+
+/**
+ * Prove that cNew = cOld + rDelta*H, i.e. both commitments hide the same bid
+ * (e.g. after rerandomizeCommitment).
+ */
+export function generateEqualityProof(
+  cOld: G1Point, cNew: G1Point, rDelta: bigint,
+  context: Uint8Array = new Uint8Array(),
+  nonce: () => bigint = randomScalar,
+): EqualityProof {
+  return proveDlogH("SBRAC_EQUAL", pointSub(cNew, cOld), rDelta, [cOld, cNew], context, nonce);
+}
+
+/** Verify an EqualityProof between cOld and cNew. */
+export function verifyEqualityProof(
+  cOld: G1Point, cNew: G1Point, proof: EqualityProof,
+  context: Uint8Array = new Uint8Array(),
+): boolean {
+  return verifyDlogH("SBRAC_EQUAL", pointSub(cNew, cOld), proof, [cOld, cNew], context);
+}